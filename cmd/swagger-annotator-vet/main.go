@@ -0,0 +1,12 @@
+// Command swagger-annotator-vet runs the swagger annotation rules as a
+// go vet-compatible analysis.Analyzer, so the same rules the
+// swagger-annotator CLI's annotate/check modes enforce by rewriting files
+// can also be driven via `go vet -vettool=swagger-annotator-vet ./...`,
+// gopls code actions, or any other analysis.Analyzer-based driver.
+package main
+
+import "swagger-annotator/internal/annotation"
+
+func main() {
+	annotation.Run()
+}
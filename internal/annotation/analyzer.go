@@ -0,0 +1,160 @@
+package annotation
+
+import (
+	"fmt"
+	"go/ast"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const analyzerDoc = `report exported request/response types missing an up-to-date swagger @name annotation
+
+The swaggerannotation analyzer flags exported types under the configured
+variant directories (see .swagger-annotator.yaml) whose trailing
+"// @name <prefix>.<version>.<Name>" comment is missing or stale, and
+attaches a SuggestedFix with the edit that would add or update it. This
+lets the same rules that drive "swagger-annotator -mode=check" run under
+go vet, gopls code actions, or any other analysis.Analyzer driver.`
+
+// Analyzer exposes the annotation rules as a golang.org/x/tools/go/analysis
+// Analyzer, with SuggestedFixes carrying the same edits the CLI would make.
+// It reads project layout from .swagger-annotator.yaml the same way the CLI
+// does; files outside the configured variant directories are skipped.
+var Analyzer = &analysis.Analyzer{
+	Name: "swaggerannotation",
+	Doc:  analyzerDoc,
+	Run:  runAnalyzer,
+}
+
+func runAnalyzer(pass *analysis.Pass) (interface{}, error) {
+	cfg, err := LoadConfig("")
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	projectName := getProjectPrefix(cfg)
+
+	for _, file := range pass.Files {
+		filename := pass.Fset.Position(file.Pos()).Filename
+		variant, version, ok := resolveVariant(filename, cfg)
+		if !ok {
+			continue
+		}
+
+		prefix := fmt.Sprintf("%s.%s.", projectName, version)
+		analyzeFile(pass, file, variant, prefix, cfg)
+	}
+
+	return nil, nil
+}
+
+// resolveVariant reports which configured variant directory filename lives
+// under, and the version path segment directly beneath it, mirroring the
+// layout processSourceFile expects: <BasePath>/<variant.Dir>/<version>/...
+func resolveVariant(filename string, cfg *Config) (VariantConfig, string, bool) {
+	for _, variant := range cfg.Variants {
+		marker := filepath.Join(cfg.BasePath, variant.Dir) + string(filepath.Separator)
+		idx := strings.Index(filename, marker)
+		if idx < 0 {
+			continue
+		}
+
+		rest := filename[idx+len(marker):]
+		parts := strings.Split(rest, string(filepath.Separator))
+		if len(parts) < 1 || parts[0] == "" {
+			continue
+		}
+
+		return variant, parts[0], true
+	}
+
+	return VariantConfig{}, "", false
+}
+
+func analyzeFile(pass *analysis.Pass, file *ast.File, variant VariantConfig, prefix string, cfg *Config) {
+	cmap := ast.NewCommentMap(pass.Fset, file, file.Comments)
+	if cmap == nil {
+		cmap = make(ast.CommentMap)
+	}
+
+	ignoredTypes := make(map[string]bool)
+	collectionInnerTypes := make(map[string]bool)
+	findIgnoredTypes(file, cmap, ignoredTypes)
+	findCollectionInnerTypes(file, ignoredTypes, cfg.CollectionTypes, collectionInnerTypes)
+
+	annotatedTypes := make(map[string]bool)
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		ts, ok := n.(*ast.TypeSpec)
+		if !ok || !ts.Name.IsExported() || ignoredTypes[ts.Name.Name] {
+			return true
+		}
+
+		info := extractTypeInfo(ts)
+		if info.Name == "" {
+			return true
+		}
+
+		mainName, itemName := generateAnnotationNameWithContext(info, variant, cfg, collectionInnerTypes)
+
+		if mainName != "" && !annotatedTypes[mainName] {
+			reportStaleAnnotation(pass, file, ts, prefix, mainName)
+			annotatedTypes[mainName] = true
+		}
+
+		if itemName != "" && !annotatedTypes[itemName] && info.InnerType != "" {
+			ast.Inspect(file, func(inner ast.Node) bool {
+				its, ok := inner.(*ast.TypeSpec)
+				if !ok || its.Name.Name != info.InnerType || ignoredTypes[its.Name.Name] {
+					return true
+				}
+				reportStaleAnnotation(pass, file, its, prefix, itemName)
+				annotatedTypes[itemName] = true
+				return false
+			})
+		}
+
+		return true
+	})
+}
+
+// reportStaleAnnotation reports a diagnostic, with a suggested fix, when ts
+// doesn't already carry an up-to-date "// @name <prefix><name>" comment.
+func reportStaleAnnotation(pass *analysis.Pass, file *ast.File, ts *ast.TypeSpec, prefix, name string) {
+	expected := fmt.Sprintf("// @name %s%s", prefix, name)
+
+	if c := findAnnotationComment(pass.Fset, file.Comments, ts); c != nil {
+		if c.Text == expected {
+			return // already up to date
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:     c.Pos(),
+			End:     c.End(),
+			Message: fmt.Sprintf("%s has a stale swagger annotation, want %q", ts.Name.Name, expected),
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message: "update @name annotation",
+				TextEdits: []analysis.TextEdit{{
+					Pos:     c.Pos(),
+					End:     c.End(),
+					NewText: []byte(expected),
+				}},
+			}},
+		})
+		return
+	}
+
+	pass.Report(analysis.Diagnostic{
+		Pos:     ts.End(),
+		End:     ts.End(),
+		Message: fmt.Sprintf("%s is missing a swagger @name annotation", ts.Name.Name),
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message: "add @name annotation",
+			TextEdits: []analysis.TextEdit{{
+				Pos:     ts.End(),
+				End:     ts.End(),
+				NewText: []byte(" " + expected),
+			}},
+		}},
+	})
+}
@@ -0,0 +1,17 @@
+package annotation
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// TestAnalyzer runs Analyzer against testdata/src/a, which exercises a
+// missing annotation, a stale one (with its SuggestedFix implicitly
+// checked via the diagnostic's message), a @swagger:ignore'd type, and an
+// already up-to-date one. See testdata/src/a/.../user.go for the "want"
+// expectations.
+func TestAnalyzer(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "example/a")
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a/...")
+}
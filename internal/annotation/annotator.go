@@ -1,14 +1,10 @@
 package annotation
 
 import (
-	"bytes"
 	"fmt"
 	"go/ast"
-	"go/parser"
 	"go/token"
 	"os"
-	"path/filepath"
-	"regexp"
 	"strings"
 )
 
@@ -19,16 +15,12 @@ const (
 	projectPrefix  = "omp-"
 )
 
-var (
-	nameRegex  = regexp.MustCompile(`//\s*@name\s+\S+`)
-	sourceDirs = []string{"request", "response"}
-	basicTypes = map[string]bool{
-		"string": true, "int": true, "int32": true, "int64": true,
-		"uint": true, "uint32": true, "uint64": true,
-		"float32": true, "float64": true, "bool": true,
-		"byte": true, "rune": true,
-	}
-)
+var basicTypes = map[string]bool{
+	"string": true, "int": true, "int32": true, "int64": true,
+	"uint": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true, "bool": true,
+	"byte": true, "rune": true,
+}
 
 // TypeInfo stores information about a type
 type TypeInfo struct {
@@ -39,28 +31,75 @@ type TypeInfo struct {
 	GenericBase string
 }
 
+// AnnotationChange describes one "// @name <prefix><name>" comment the tool
+// added or updated, for callers building a structured report.
+type AnnotationChange struct {
+	TypeName string
+	Line     int
+	OldName  string
+	NewName  string
+}
+
+// FileResult collects the annotation changes and errors recorded for a
+// single source file.
+type FileResult struct {
+	Path     string
+	Added    []AnnotationChange
+	Replaced []AnnotationChange
+	Errors   []string
+}
+
 // ProcessingResult tracks the results of processing files
 type ProcessingResult struct {
 	FilesProcessed      int
 	AnnotationsAdded    int
 	AnnotationsReplaced int
-	Errors              []error
+	// Errors holds each error's message rather than the error itself, so a
+	// structured (json/sarif) report can actually surface what went wrong:
+	// error has no exported fields, so json.Marshal on a []error serializes
+	// every entry as "{}".
+	Errors []string
+	Files  []*FileResult
 }
 
 func (r *ProcessingResult) AddFile() {
 	r.FilesProcessed++
 }
 
-func (r *ProcessingResult) AddAnnotation() {
+// fileResult returns the FileResult for path, creating it on first use.
+func (r *ProcessingResult) fileResult(path string) *FileResult {
+	for _, fr := range r.Files {
+		if fr.Path == path {
+			return fr
+		}
+	}
+	fr := &FileResult{Path: path}
+	r.Files = append(r.Files, fr)
+	return fr
+}
+
+func (r *ProcessingResult) AddAnnotation(path string, change AnnotationChange) {
 	r.AnnotationsAdded++
+	fr := r.fileResult(path)
+	fr.Added = append(fr.Added, change)
 }
 
-func (r *ProcessingResult) ReplaceAnnotation() {
+func (r *ProcessingResult) ReplaceAnnotation(path string, change AnnotationChange) {
 	r.AnnotationsReplaced++
+	fr := r.fileResult(path)
+	fr.Replaced = append(fr.Replaced, change)
 }
 
 func (r *ProcessingResult) AddError(err error) {
-	r.Errors = append(r.Errors, err)
+	r.Errors = append(r.Errors, err.Error())
+}
+
+// AddFileError records err against both the aggregate error list and path's
+// FileResult, so a structured report can show which file it came from.
+func (r *ProcessingResult) AddFileError(path string, err error) {
+	r.AddError(err)
+	fr := r.fileResult(path)
+	fr.Errors = append(fr.Errors, err.Error())
 }
 
 func (r *ProcessingResult) Summary() string {
@@ -80,88 +119,8 @@ func safePrint(format string, args ...interface{}) {
 	}
 }
 
-func Run() error {
-	projectPrefix := getProjectPrefix()
-
-	root, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("getting working directory: %w", err)
-	}
-
-	result := processSourceDirectories(root, projectPrefix)
-
-	safePrint("%s\n", result.Summary())
-
-	if result.HasErrors() {
-		return fmt.Errorf("encountered %d errors during processing", len(result.Errors))
-	}
-
-	return nil
-}
-
-func processSourceDirectories(root, projectPrefix string) *ProcessingResult {
-	result := &ProcessingResult{}
-
-	for _, dir := range sourceDirs {
-		fullPath := filepath.Join(root, basePath, dir)
-		err := filepath.Walk(fullPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err // Let filepath.Walk handle the error
-			}
-
-			if info.IsDir() || !strings.HasSuffix(info.Name(), ".go") {
-				return nil
-			}
-
-			if err := processSourceFile(root, path, projectPrefix, dir, result); err != nil {
-				// Collect errors but continue processing other files
-				result.AddError(fmt.Errorf("processing %s: %w", path, err))
-			}
-			return nil
-		})
-
-		if err != nil {
-			result.AddError(fmt.Errorf("walking directory %s: %w", fullPath, err))
-		}
-	}
-
-	return result
-}
-
-func processSourceFile(root, path, projectPrefix, dir string, result *ProcessingResult) error {
-	rel, err := filepath.Rel(filepath.Join(root, basePath), path)
-	if err != nil {
-		return fmt.Errorf("resolving relative path: %w", err)
-	}
-
-	parts := strings.Split(rel, string(filepath.Separator))
-	if len(parts) < 2 {
-		return nil // Skip files not in the expected structure
-	}
-
-	version := parts[1]
-	prefix := fmt.Sprintf("%s.%s.", projectPrefix, version)
-
-	result.AddFile()
-	return processFile(path, prefix, dir, result)
-}
-
-func getProjectPrefix() string {
-	repo := os.Getenv(projectEnvVar)
-	if repo != "" {
-		parts := strings.Split(repo, "/")
-		if len(parts) == 2 {
-			return getProjectName(parts[1])
-		}
-	}
-	if cwd, err := os.Getwd(); err == nil {
-		return getProjectName(filepath.Base(cwd))
-	}
-	return "project"
-}
-
-func getProjectName(input string) string {
-	return strings.TrimPrefix(input, projectPrefix)
+func getProjectName(input string, cfg *Config) string {
+	return strings.TrimPrefix(input, cfg.ProjectPrefix)
 }
 
 func extractTypeInfo(ts *ast.TypeSpec) TypeInfo {
@@ -205,139 +164,104 @@ func handleIdentType(info TypeInfo, ident *ast.Ident) TypeInfo {
 	return info
 }
 
-func generateAnnotationNameWithContext(info TypeInfo, variant string, searchResponseInnerTypes map[string]bool) (string, string) {
+func generateAnnotationNameWithContext(info TypeInfo, variant VariantConfig, cfg *Config, collectionInnerTypes map[string]bool) (string, string) {
 	if info.Name == "" {
 		return "", ""
 	}
 
+	itemSuffix := cfg.ItemSuffix + variant.Suffix
 	var mainName, itemName string
 
-	if (info.IsGeneric || info.IsAlias) && info.GenericBase == "SearchResponse" {
-		mainName = info.Name + "Res"
+	if (info.IsGeneric || info.IsAlias) && contains(cfg.CollectionTypes, info.GenericBase) {
+		mainName = info.Name + variant.Suffix
 		if info.InnerType != "" {
 			itemBase := strings.TrimSuffix(strings.TrimSuffix(info.InnerType, "Response"), "Request")
-			if variant == "response" {
-				itemName = itemBase + "ItemRes"
-			} else {
-				itemName = itemBase + "ItemReq"
-			}
+			itemName = itemBase + itemSuffix
 		}
 	} else {
 		base := strings.TrimSuffix(strings.TrimSuffix(info.Name, "Response"), "Request")
-		if searchResponseInnerTypes[info.Name] {
-			if variant == "response" {
-				mainName = base + "ItemRes"
-			} else {
-				mainName = base + "ItemReq"
-			}
+		if collectionInnerTypes[info.Name] {
+			mainName = base + itemSuffix
 		} else {
-			if variant == "response" {
-				mainName = base + "Res"
-			} else {
-				mainName = base + "Req"
-			}
+			mainName = base + variant.Suffix
 		}
 	}
 
 	return mainName, itemName
 }
 
-func processFile(filename, prefix, variant string, result *ProcessingResult) error {
-	src, err := os.ReadFile(filename)
-	if err != nil {
-		return fmt.Errorf("reading file: %w", err)
-	}
-
-	fset := token.NewFileSet()
-	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
-	if err != nil {
-		return fmt.Errorf("parsing file: %w", err)
-	}
-
-	lines := bytes.Split(src, []byte("\n"))
-	annotations := make(map[int]string)
-	annotatedTypes := make(map[string]bool)
-	ignoredTypes := make(map[string]bool)
-	searchResponseInnerTypes := make(map[string]bool)
-
-	// Multi-pass processing
-	findIgnoredTypes(f, fset, lines, ignoredTypes)
-	findSearchResponseInnerTypes(f, ignoredTypes, searchResponseInnerTypes)
-	addAnnotations(f, fset, variant, ignoredTypes, searchResponseInnerTypes, annotations, annotatedTypes)
-
-	// Apply annotations to lines
-	annotationsApplied := applyAnnotations(lines, annotations, prefix, filename, result)
-
-	if annotationsApplied > 0 {
-		if err := writeFile(filename, lines); err != nil {
-			return fmt.Errorf("writing file: %w", err)
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
 		}
 	}
-
-	return nil
+	return false
 }
 
-func findIgnoredTypes(f *ast.File, fset *token.FileSet, lines [][]byte, ignoredTypes map[string]bool) {
-	ast.Inspect(f, func(n ast.Node) bool {
-		ts, ok := n.(*ast.TypeSpec)
-		if !ok || !ts.Name.IsExported() {
-			return true
+func findIgnoredTypes(f *ast.File, cmap ast.CommentMap, ignoredTypes map[string]bool) {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
 		}
 
-		// Check documentation comments
-		if ts.Doc != nil {
-			for _, comment := range ts.Doc.List {
-				if strings.Contains(comment.Text, "@swagger:ignore") {
-					ignoredTypes[ts.Name.Name] = true
-					return true
-				}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || !ts.Name.IsExported() {
+				continue
 			}
-		}
 
-		// Check inline comments on the same line
-		position := fset.Position(ts.Pos())
-		if position.Line > 0 && position.Line <= len(lines) {
-			lineContent := string(lines[position.Line-1])
-			if strings.Contains(lineContent, "@swagger:ignore") {
+			// In the non-parenthesized "type Foo struct{}" form, go/parser
+			// attaches the leading doc comment to the enclosing GenDecl
+			// rather than the TypeSpec, so ts.Doc is nil there; fall back to
+			// gd.Doc in that case. A parenthesized "type ( ... )" block
+			// already gets ts.Doc populated per spec, and gd.Doc on a
+			// multi-spec block belongs to the block as a whole, not to any
+			// one spec, so it's only consulted for the lone-spec form.
+			ignored := hasIgnoreMarker(ts.Doc) || hasIgnoreMarker(cmap[ts]...)
+			if !ignored && gd.Lparen == token.NoPos {
+				ignored = hasIgnoreMarker(gd.Doc)
+			}
+			if ignored {
 				ignoredTypes[ts.Name.Name] = true
-				return true
 			}
 		}
+	}
+}
 
-		// Check all comments in the file that might belong to this type
-		for _, cg := range f.Comments {
-			for _, c := range cg.List {
-				if strings.Contains(c.Text, "@swagger:ignore") {
-					// Check if the comment is near the type
-					commentPos := fset.Position(c.Pos())
-					typePos := fset.Position(ts.Pos())
-					if commentPos.Line >= typePos.Line-1 && commentPos.Line <= typePos.Line+1 {
-						ignoredTypes[ts.Name.Name] = true
-						return true
-					}
-				}
+func hasIgnoreMarker(groups ...*ast.CommentGroup) bool {
+	for _, cg := range groups {
+		if cg == nil {
+			continue
+		}
+		for _, c := range cg.List {
+			if strings.Contains(c.Text, "@swagger:ignore") {
+				return true
 			}
 		}
-
-		return true
-	})
+	}
+	return false
 }
 
-func findSearchResponseInnerTypes(f *ast.File, ignoredTypes, searchResponseInnerTypes map[string]bool) {
+func findCollectionInnerTypes(f *ast.File, ignoredTypes map[string]bool, collectionTypes []string, collectionInnerTypes map[string]bool) {
 	ast.Inspect(f, func(n ast.Node) bool {
 		ts, ok := n.(*ast.TypeSpec)
 		if !ok || !ts.Name.IsExported() || ignoredTypes[ts.Name.Name] {
 			return true
 		}
 		info := extractTypeInfo(ts)
-		if (info.IsGeneric || info.IsAlias) && info.GenericBase == "SearchResponse" && info.InnerType != "" {
-			searchResponseInnerTypes[info.InnerType] = true
+		if (info.IsGeneric || info.IsAlias) && contains(collectionTypes, info.GenericBase) && info.InnerType != "" {
+			collectionInnerTypes[info.InnerType] = true
 		}
 		return true
 	})
 }
 
-func addAnnotations(f *ast.File, fset *token.FileSet, variant string, ignoredTypes, searchResponseInnerTypes map[string]bool, annotations map[int]string, annotatedTypes map[string]bool) {
+func addAnnotations(f *ast.File, fset *token.FileSet, cmap ast.CommentMap, variant VariantConfig, cfg *Config, prefix string, ignoredTypes, collectionInnerTypes map[string]bool, filename string, result *ProcessingResult) bool {
+	annotatedTypes := make(map[string]bool)
+	changed := false
+
 	ast.Inspect(f, func(n ast.Node) bool {
 		ts, ok := n.(*ast.TypeSpec)
 		if !ok || !ts.Name.IsExported() || ignoredTypes[ts.Name.Name] {
@@ -349,71 +273,86 @@ func addAnnotations(f *ast.File, fset *token.FileSet, variant string, ignoredTyp
 			return true
 		}
 
-		mainName, itemName := generateAnnotationNameWithContext(info, variant, searchResponseInnerTypes)
+		mainName, itemName := generateAnnotationNameWithContext(info, variant, cfg, collectionInnerTypes)
 
 		if mainName != "" && !annotatedTypes[mainName] {
-			endLine := fset.Position(ts.End()).Line - 1
-			annotations[endLine] = mainName
+			if annotateTypeSpec(f, fset, cmap, ts, prefix, mainName, filename, result) {
+				changed = true
+			}
 			annotatedTypes[mainName] = true
 		}
 
-		if itemName != "" && !annotatedTypes[itemName] {
-			if info.InnerType != "" {
-				ast.Inspect(f, func(inner ast.Node) bool {
-					if its, ok := inner.(*ast.TypeSpec); ok && its.Name.Name == info.InnerType && !ignoredTypes[its.Name.Name] {
-						innerLine := fset.Position(its.End()).Line - 1
-						annotations[innerLine] = itemName
-						annotatedTypes[itemName] = true
-						return false
-					}
+		if itemName != "" && !annotatedTypes[itemName] && info.InnerType != "" {
+			ast.Inspect(f, func(inner ast.Node) bool {
+				its, ok := inner.(*ast.TypeSpec)
+				if !ok || its.Name.Name != info.InnerType || ignoredTypes[its.Name.Name] {
 					return true
-				})
-			}
+				}
+				if annotateTypeSpec(f, fset, cmap, its, prefix, itemName, filename, result) {
+					changed = true
+				}
+				annotatedTypes[itemName] = true
+				return false
+			})
 		}
+
 		return true
 	})
-}
-
-func applyAnnotations(lines [][]byte, annotations map[int]string, prefix, filename string, result *ProcessingResult) int {
-	annotationsApplied := 0
 
-	for i := range lines {
-		if name, ok := annotations[i]; ok {
-			expected := fmt.Sprintf("@name %s%s", prefix, name)
-			lineStr := strings.TrimSpace(string(lines[i]))
-
-			if strings.Contains(lineStr, expected) {
-				continue // Annotation already exists
-			}
+	return changed
+}
 
-			if loc := nameRegex.FindStringIndex(lineStr); loc != nil {
-				// Replace the existing annotation
-				lines[i] = append(lines[i][:loc[0]], []byte(fmt.Sprintf(" // %s", expected))...)
-				safePrint("replaced annotation in %s: %s\n", filename, name)
-				result.ReplaceAnnotation()
-			} else {
-				// Add new annotation
-				annotation := fmt.Sprintf(" // %s", expected)
-				lines[i] = append(lines[i], []byte(annotation)...)
-				safePrint("added annotation to %s: %s\n", filename, name)
-				result.AddAnnotation()
+// findAnnotationComment returns the existing "// @name ..." comment attached
+// to ts, if any, by scanning comments for one sitting on ts's end line. It's
+// looked up in comments (f.Comments) rather than cmap[ts]: when ts is the
+// last declaration in the file, ast.NewCommentMap associates its trailing
+// comment with the *ast.File instead, not the TypeSpec. Both the in-place
+// rewriter (annotateTypeSpec) and the analysis.Analyzer
+// (reportStaleAnnotation) call this, so there's one definition of what
+// counts as "the existing annotation for ts", not two that can drift apart.
+func findAnnotationComment(fset *token.FileSet, comments []*ast.CommentGroup, ts *ast.TypeSpec) *ast.Comment {
+	endLine := fset.Position(ts.End()).Line
+	for _, cg := range comments {
+		for _, c := range cg.List {
+			if fset.Position(c.Slash).Line == endLine && strings.Contains(c.Text, "@name") {
+				return c
 			}
-			annotationsApplied++
 		}
 	}
-
-	return annotationsApplied
+	return nil
 }
 
-func writeFile(filename string, lines [][]byte) error {
-	var output bytes.Buffer
-	if _, err := output.Write(bytes.Join(lines, []byte("\n"))); err != nil {
-		return fmt.Errorf("writing to buffer: %w", err)
-	}
-
-	if err := os.WriteFile(filename, output.Bytes(), filePermission); err != nil {
-		return fmt.Errorf("writing to file: %w", err)
+// annotateTypeSpec ensures ts carries a trailing "// @name <prefix><name>"
+// comment, mutating an existing one in place if present so repeated runs
+// stay idempotent instead of appending duplicates.
+func annotateTypeSpec(f *ast.File, fset *token.FileSet, cmap ast.CommentMap, ts *ast.TypeSpec, prefix, name, filename string, result *ProcessingResult) bool {
+	expected := fmt.Sprintf("// @name %s%s", prefix, name)
+	fullName := prefix + name
+	endLine := fset.Position(ts.End()).Line
+
+	if c := findAnnotationComment(fset, f.Comments, ts); c != nil {
+		if c.Text == expected {
+			return false // already up to date
+		}
+		oldName := strings.TrimSpace(strings.TrimPrefix(c.Text, "// @name"))
+		c.Text = expected
+		safePrint("replaced annotation in %s: %s\n", filename, name)
+		result.ReplaceAnnotation(filename, AnnotationChange{
+			TypeName: ts.Name.Name,
+			Line:     endLine,
+			OldName:  oldName,
+			NewName:  fullName,
+		})
+		return true
 	}
 
-	return nil
+	comment := &ast.Comment{Slash: ts.End(), Text: expected}
+	cmap[ts] = append(cmap[ts], &ast.CommentGroup{List: []*ast.Comment{comment}})
+	safePrint("added annotation to %s: %s\n", filename, name)
+	result.AddAnnotation(filename, AnnotationChange{
+		TypeName: ts.Name.Name,
+		Line:     endLine,
+		NewName:  fullName,
+	})
+	return true
 }
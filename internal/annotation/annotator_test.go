@@ -0,0 +1,54 @@
+package annotation
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+// TestFindIgnoredTypes_LoneAndParenthesizedForms verifies @swagger:ignore is
+// honored both on a standalone "type Foo struct{}" declaration, where the
+// doc comment attaches to the enclosing GenDecl rather than the TypeSpec,
+// and inside a parenthesized "type ( ... )" block, where it attaches to the
+// TypeSpec directly.
+func TestFindIgnoredTypes_LoneAndParenthesizedForms(t *testing.T) {
+	const src = `package response
+
+// @swagger:ignore
+type InternalRes struct {
+	Foo string
+}
+
+type (
+	// @swagger:ignore
+	BlockIgnoredRes struct {
+		Bar string
+	}
+
+	KeptRes struct {
+		Baz string
+	}
+)
+`
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "response.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+
+	cmap := ast.NewCommentMap(fset, f, f.Comments)
+	ignored := make(map[string]bool)
+	findIgnoredTypes(f, cmap, ignored)
+
+	want := map[string]bool{"InternalRes": true, "BlockIgnoredRes": true}
+	for name := range want {
+		if !ignored[name] {
+			t.Errorf("expected %s to be ignored, got ignored=%v", name, ignored)
+		}
+	}
+	if ignored["KeptRes"] {
+		t.Errorf("KeptRes has no @swagger:ignore marker but was ignored")
+	}
+}
@@ -0,0 +1,117 @@
+package annotation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/afero"
+)
+
+// CheckResult reports which annotation-owned files Check would rewrite.
+type CheckResult struct {
+	Changed []string
+	Added   []string
+
+	// Result is the ProcessingResult from the in-memory annotator run Check
+	// performed to compute the diff, for callers that want a structured
+	// (json/sarif) report of what changed rather than just the path lists
+	// above.
+	Result *ProcessingResult
+}
+
+// HasChanges reports whether the annotator would rewrite any file.
+func (r *CheckResult) HasChanges() bool {
+	return len(r.Changed) > 0 || len(r.Added) > 0
+}
+
+// Check runs the annotator in-memory, via Runner.WithDryRun, and diffs the
+// result against the git blob at HEAD for each file it touched. Because the
+// run never writes to the real filesystem, "-mode=check" can't leave the
+// working tree modified as a side effect of checking it, and because the
+// comparison is against HEAD rather than the pre-run worktree status, it
+// doesn't depend on (or get confused by) pre-existing uncommitted changes.
+func Check(configPath string) (*CheckResult, error) {
+	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("opening git repository: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("opening worktree: %w", err)
+	}
+	root := worktree.Filesystem.Root()
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("resolving HEAD: %w", err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("reading HEAD commit: %w", err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("reading HEAD tree: %w", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting working directory: %w", err)
+	}
+
+	runner := NewRunner(afero.NewOsFs(), cwd).WithConfigPath(configPath).WithDryRun(true)
+	result, err := runner.Run(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if result.HasErrors() {
+		return &CheckResult{Result: result}, fmt.Errorf("encountered %d errors during processing", len(result.Errors))
+	}
+
+	check := &CheckResult{Result: result}
+	for _, fr := range result.Files {
+		if len(fr.Added) == 0 && len(fr.Replaced) == 0 {
+			continue
+		}
+
+		rel, err := filepath.Rel(root, fr.Path)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s relative to repo root: %w", fr.Path, err)
+		}
+		rel = filepath.ToSlash(rel)
+
+		newContent, err := afero.ReadFile(runner.Fs(), fr.Path)
+		if err != nil {
+			return nil, fmt.Errorf("reading annotated %s: %w", fr.Path, err)
+		}
+
+		headFile, err := tree.File(rel)
+		if err == object.ErrFileNotFound {
+			check.Added = append(check.Added, rel)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s from HEAD: %w", rel, err)
+		}
+
+		headContent, err := headFile.Contents()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s contents from HEAD: %w", rel, err)
+		}
+
+		if headContent != string(newContent) {
+			check.Changed = append(check.Changed, rel)
+		}
+	}
+
+	sort.Strings(check.Changed)
+	sort.Strings(check.Added)
+
+	return check, nil
+}
@@ -0,0 +1,98 @@
+package annotation
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestCheck_ClassifiesAddedAndChanged(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GITHUB_REPOSITORY", "")
+
+	origWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(origWD) })
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("worktree: %v", err)
+	}
+
+	variantDir := filepath.Join(dir, "internal/delivery/http/request/v1")
+	if err := os.MkdirAll(variantDir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	// DefaultConfig's variants also include "response"; create it (empty) so
+	// the walk over that variant directory doesn't fail for simply not
+	// existing.
+	if err := os.MkdirAll(filepath.Join(dir, "internal/delivery/http/response"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	// Tracked, committed without its swagger annotation: Check should
+	// classify it as "changed".
+	changedRel := "internal/delivery/http/request/v1/changed.go"
+	changedPath := filepath.Join(dir, changedRel)
+	if err := os.WriteFile(changedPath, []byte("package request\n\ntype ChangedReq struct {\n\tName string\n}\n"), 0644); err != nil {
+		t.Fatalf("write changed.go: %v", err)
+	}
+	if _, err := worktree.Add(changedRel); err != nil {
+		t.Fatalf("git add: %v", err)
+	}
+
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(0, 0)}
+	if _, err := worktree.Commit("initial", &git.CommitOptions{Author: sig}); err != nil {
+		t.Fatalf("git commit: %v", err)
+	}
+
+	// Untracked, never committed: Check should classify it as "added".
+	addedRel := "internal/delivery/http/request/v1/added.go"
+	addedPath := filepath.Join(dir, addedRel)
+	if err := os.WriteFile(addedPath, []byte("package request\n\ntype AddedReq struct {\n\tName string\n}\n"), 0644); err != nil {
+		t.Fatalf("write added.go: %v", err)
+	}
+
+	result, err := Check("")
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	sort.Strings(result.Changed)
+	sort.Strings(result.Added)
+
+	if len(result.Changed) != 1 || result.Changed[0] != changedRel {
+		t.Errorf("expected Changed=[%s], got %v", changedRel, result.Changed)
+	}
+	if len(result.Added) != 1 || result.Added[0] != addedRel {
+		t.Errorf("expected Added=[%s], got %v", addedRel, result.Added)
+	}
+	if !result.HasChanges() {
+		t.Errorf("expected HasChanges() to be true")
+	}
+
+	// Check must run purely in-memory: the tracked file on disk should be
+	// untouched by running it, since "-mode=check" isn't supposed to mutate
+	// the working tree.
+	onDisk, err := os.ReadFile(changedPath)
+	if err != nil {
+		t.Fatalf("reading changed.go after Check: %v", err)
+	}
+	if string(onDisk) != "package request\n\ntype ChangedReq struct {\n\tName string\n}\n" {
+		t.Errorf("Check must not write to the real filesystem, but changed.go was rewritten:\n%s", onDisk)
+	}
+}
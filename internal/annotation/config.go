@@ -0,0 +1,111 @@
+package annotation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// configFileName is the name looked up when searching upward from the
+// working directory for project-specific settings.
+const configFileName = ".swagger-annotator.yaml"
+
+// VariantConfig describes one source-tree variant (e.g. request/response)
+// and the annotation suffix its types should receive.
+type VariantConfig struct {
+	Dir    string `yaml:"dir"`
+	Suffix string `yaml:"suffix"`
+}
+
+// Config holds everything about a project's layout that used to be baked
+// into package constants, so teams whose tree doesn't match the
+// internal/delivery/http/{request,response} convention can still use the
+// tool.
+type Config struct {
+	BasePath        string          `yaml:"basePath"`
+	ProjectPrefix   string          `yaml:"projectPrefix"`
+	Variants        []VariantConfig `yaml:"variants"`
+	CollectionTypes []string        `yaml:"collectionTypes"`
+	ItemSuffix      string          `yaml:"itemSuffix"`
+}
+
+// DefaultConfig returns the tool's historical, hardcoded behavior.
+func DefaultConfig() *Config {
+	return &Config{
+		BasePath:      basePath,
+		ProjectPrefix: projectPrefix,
+		Variants: []VariantConfig{
+			{Dir: "request", Suffix: "Req"},
+			{Dir: "response", Suffix: "Res"},
+		},
+		CollectionTypes: []string{"SearchResponse"},
+		ItemSuffix:      "Item",
+	}
+}
+
+// LoadConfig loads the project config from the real filesystem, searching
+// upward from the current working directory when path is empty. Callers
+// that already hold a Runner (and so may be pointed at an in-memory
+// filesystem) get their config through Runner.Run instead.
+func LoadConfig(path string) (*Config, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting working directory: %w", err)
+	}
+	return loadConfigFS(afero.NewOsFs(), cwd, path)
+}
+
+// loadConfigFS loads the project config through fs. If path is non-empty it
+// is read directly; otherwise configFileName is searched for upward from
+// cwd. When no config file is found, DefaultConfig is returned so the tool
+// keeps working unconfigured.
+func loadConfigFS(fs afero.Fs, cwd, path string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	if path == "" {
+		found, err := findConfigFileFS(fs, cwd)
+		if err != nil {
+			return nil, err
+		}
+		if found == "" {
+			return cfg, nil
+		}
+		path = found
+	}
+
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// findConfigFileFS walks upward from dir looking for configFileName,
+// stopping at the filesystem root. It returns an empty path when nothing is
+// found.
+func findConfigFileFS(fs afero.Fs, dir string) (string, error) {
+	for {
+		candidate := filepath.Join(dir, configFileName)
+		exists, err := afero.Exists(fs, candidate)
+		if err != nil {
+			return "", fmt.Errorf("checking %s: %w", candidate, err)
+		}
+		if exists {
+			return candidate, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
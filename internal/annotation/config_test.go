@@ -0,0 +1,89 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestLoadConfigFS_DefaultsWhenNoConfigFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	cfg, err := loadConfigFS(fs, "/project/internal/delivery/http/request", "")
+	if err != nil {
+		t.Fatalf("loadConfigFS: %v", err)
+	}
+
+	if cfg.BasePath != basePath || cfg.ProjectPrefix != projectPrefix {
+		t.Fatalf("expected DefaultConfig, got %+v", cfg)
+	}
+}
+
+func TestLoadConfigFS_FindsConfigSearchingUpward(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/project/.swagger-annotator.yaml", `
+basePath: api/http
+projectPrefix: demo-
+variants:
+  - dir: req
+    suffix: Request
+collectionTypes:
+  - ListResponse
+itemSuffix: Entry
+`)
+
+	cfg, err := loadConfigFS(fs, "/project/internal/delivery/http/request/v1", "")
+	if err != nil {
+		t.Fatalf("loadConfigFS: %v", err)
+	}
+
+	if cfg.BasePath != "api/http" {
+		t.Errorf("BasePath = %q, want %q", cfg.BasePath, "api/http")
+	}
+	if cfg.ProjectPrefix != "demo-" {
+		t.Errorf("ProjectPrefix = %q, want %q", cfg.ProjectPrefix, "demo-")
+	}
+	if len(cfg.Variants) != 1 || cfg.Variants[0].Dir != "req" || cfg.Variants[0].Suffix != "Request" {
+		t.Errorf("Variants = %+v, want [{req Request}]", cfg.Variants)
+	}
+	if len(cfg.CollectionTypes) != 1 || cfg.CollectionTypes[0] != "ListResponse" {
+		t.Errorf("CollectionTypes = %v, want [ListResponse]", cfg.CollectionTypes)
+	}
+	if cfg.ItemSuffix != "Entry" {
+		t.Errorf("ItemSuffix = %q, want %q", cfg.ItemSuffix, "Entry")
+	}
+}
+
+func TestLoadConfigFS_ExplicitPathBypassesSearch(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/other/place.yaml", "basePath: wherever\n")
+
+	cfg, err := loadConfigFS(fs, "/project", "/other/place.yaml")
+	if err != nil {
+		t.Fatalf("loadConfigFS: %v", err)
+	}
+	if cfg.BasePath != "wherever" {
+		t.Errorf("BasePath = %q, want %q", cfg.BasePath, "wherever")
+	}
+}
+
+func TestLoadConfigFS_InvalidYAML(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeFile(t, fs, "/project/.swagger-annotator.yaml", "basePath: [unterminated\n")
+
+	if _, err := loadConfigFS(fs, "/project", ""); err == nil {
+		t.Fatal("expected an error for invalid YAML")
+	}
+}
+
+func TestFindConfigFileFS_StopsAtRoot(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	found, err := findConfigFileFS(fs, "/a/b/c")
+	if err != nil {
+		t.Fatalf("findConfigFileFS: %v", err)
+	}
+	if found != "" {
+		t.Errorf("expected no config file found, got %q", found)
+	}
+}
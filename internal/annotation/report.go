@@ -0,0 +1,194 @@
+package annotation
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// ReportText is the default, human-readable one-line summary.
+	ReportText = "text"
+	// ReportJSON renders ProcessingResult as indented JSON.
+	ReportJSON = "json"
+	// ReportSARIF renders ProcessingResult as a SARIF 2.1.0 log.
+	ReportSARIF = "sarif"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// Render formats the result for CI consumption. format is one of
+// ReportText, ReportJSON, or ReportSARIF ("" is treated as ReportText).
+// checkMode raises SARIF result levels from "note" to "warning", since a
+// failing check means the repo was about to be merged with stale
+// annotations rather than the tool simply fixing them in place.
+func (r *ProcessingResult) Render(format string, checkMode bool) (string, error) {
+	switch format {
+	case "", ReportText:
+		return r.Summary(), nil
+	case ReportJSON:
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling json report: %w", err)
+		}
+		return string(data), nil
+	case ReportSARIF:
+		data, err := json.MarshalIndent(r.sarifLog(checkMode), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling sarif report: %w", err)
+		}
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+// sarifLog and friends are a minimal SARIF 2.1.0 object graph, just enough
+// of the spec for GitHub's code-scanning upload action and other
+// SARIF-aware reviewers to render one result per added/replaced annotation.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string    `json:"id"`
+	ShortDescription sarifText `json:"shortDescription"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	Fixes     []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+type sarifFix struct {
+	Description     sarifText             `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion          `json:"deletedRegion"`
+	InsertedContent sarifArtifactContent `json:"insertedContent"`
+}
+
+type sarifArtifactContent struct {
+	Text string `json:"text"`
+}
+
+// sarifLog builds the SARIF log for r. The rule id matches Analyzer.Name so
+// a SARIF consumer and "go vet -vettool=..." report the same finding under
+// the same identity.
+func (r *ProcessingResult) sarifLog(checkMode bool) sarifLog {
+	level := "note"
+	if checkMode {
+		level = "warning"
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name: "swagger-annotator",
+				Rules: []sarifRule{{
+					ID:               Analyzer.Name,
+					ShortDescription: sarifText{Text: "Exported request/response types must carry an up-to-date swagger @name annotation."},
+				}},
+			},
+		},
+	}
+
+	for _, fr := range r.Files {
+		for _, change := range fr.Added {
+			run.Results = append(run.Results, sarifResultFor(fr.Path, change, level, false))
+		}
+		for _, change := range fr.Replaced {
+			run.Results = append(run.Results, sarifResultFor(fr.Path, change, level, true))
+		}
+	}
+
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+}
+
+// sarifResultFor builds one SARIF result for change, replaced distinguishing
+// an added annotation from a stale one being updated. The region's column is
+// always 1: AnnotationChange only tracks the line the "// @name ..." comment
+// sits on, not its column, since every annotation is its own trailing or
+// standalone comment.
+func sarifResultFor(path string, change AnnotationChange, level string, replaced bool) sarifResult {
+	message := fmt.Sprintf("%s is missing a swagger @name annotation, would add %q", change.TypeName, change.NewName)
+	if replaced {
+		message = fmt.Sprintf("%s has a stale swagger @name annotation %q, would replace with %q", change.TypeName, change.OldName, change.NewName)
+	}
+
+	region := sarifRegion{StartLine: change.Line, StartColumn: 1}
+	insertedText := fmt.Sprintf("// @name %s", change.NewName)
+
+	return sarifResult{
+		RuleID:  Analyzer.Name,
+		Level:   level,
+		Message: sarifText{Text: message},
+		Locations: []sarifLocation{{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: path},
+				Region:           region,
+			},
+		}},
+		Fixes: []sarifFix{{
+			Description: sarifText{Text: "update @name annotation"},
+			ArtifactChanges: []sarifArtifactChange{{
+				ArtifactLocation: sarifArtifactLocation{URI: path},
+				Replacements: []sarifReplacement{{
+					DeletedRegion:   region,
+					InsertedContent: sarifArtifactContent{Text: insertedText},
+				}},
+			}},
+		}},
+	}
+}
@@ -0,0 +1,62 @@
+package annotation
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestRender_JSONSurfacesErrorMessages guards against ProcessingResult.Errors
+// round-tripping through json.Marshal as a useless "{}": a plain error value
+// has no exported fields, so the top-level field has to carry strings, not
+// errors, for a CI system to see what actually went wrong.
+func TestRender_JSONSurfacesErrorMessages(t *testing.T) {
+	result := &ProcessingResult{FilesProcessed: 1}
+	result.AddFileError("bad.go", errors.New("parsing file: unexpected EOF"))
+
+	rendered, err := result.Render(ReportJSON, false)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if !strings.Contains(rendered, "unexpected EOF") {
+		t.Fatalf("expected error message in rendered JSON, got:\n%s", rendered)
+	}
+
+	var decoded ProcessingResult
+	if err := json.Unmarshal([]byte(rendered), &decoded); err != nil {
+		t.Fatalf("unmarshaling rendered JSON: %v", err)
+	}
+	if len(decoded.Errors) != 1 || decoded.Errors[0] != "parsing file: unexpected EOF" {
+		t.Fatalf("expected Errors to decode back to the original message, got %v", decoded.Errors)
+	}
+}
+
+func TestRender_SARIFLevelRaisedInCheckMode(t *testing.T) {
+	result := &ProcessingResult{}
+	result.AddAnnotation("user.go", AnnotationChange{TypeName: "CreateUserReq", Line: 5, NewName: "omp-project.v1.CreateUserReqReq"})
+
+	annotateReport, err := result.Render(ReportSARIF, false)
+	if err != nil {
+		t.Fatalf("Render (annotate): %v", err)
+	}
+	if !strings.Contains(annotateReport, `"level": "note"`) {
+		t.Fatalf("expected note-level SARIF outside check mode, got:\n%s", annotateReport)
+	}
+
+	checkReport, err := result.Render(ReportSARIF, true)
+	if err != nil {
+		t.Fatalf("Render (check): %v", err)
+	}
+	if !strings.Contains(checkReport, `"level": "warning"`) {
+		t.Fatalf("expected warning-level SARIF in check mode, got:\n%s", checkReport)
+	}
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	result := &ProcessingResult{}
+	if _, err := result.Render("yaml", false); err == nil {
+		t.Fatal("expected an error for an unknown report format")
+	}
+}
@@ -0,0 +1,233 @@
+package annotation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+// Runner processes a project's source tree through an afero.Fs, so callers
+// can point it at the real disk or an in-memory filesystem (tests, dry
+// runs) without the rest of the package knowing the difference.
+type Runner struct {
+	fs         afero.Fs
+	cwd        string
+	configPath string
+}
+
+// NewRunner builds a Runner rooted at cwd, reading and writing through fs.
+func NewRunner(fs afero.Fs, cwd string) *Runner {
+	return &Runner{fs: fs, cwd: cwd}
+}
+
+// WithConfigPath pins the config file to load, bypassing upward search.
+func (r *Runner) WithConfigPath(path string) *Runner {
+	clone := *r
+	clone.configPath = path
+	return &clone
+}
+
+// WithDryRun layers an in-memory overlay over the Runner's filesystem so
+// writes never touch the underlying disk; reads still fall through to it.
+// Call Fs() afterward to inspect the content a real run would have written.
+func (r *Runner) WithDryRun(dryRun bool) *Runner {
+	if !dryRun {
+		return r
+	}
+	clone := *r
+	clone.fs = afero.NewCopyOnWriteFs(r.fs, afero.NewMemMapFs())
+	return &clone
+}
+
+// Fs returns the filesystem the Runner reads and writes through.
+func (r *Runner) Fs() afero.Fs {
+	return r.fs
+}
+
+// Run processes the project rooted at r.cwd and reports what it did.
+func (r *Runner) Run(ctx context.Context) (*ProcessingResult, error) {
+	cfg, err := loadConfigFS(r.fs, r.cwd, r.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	return r.processSourceDirectories(ctx, r.getProjectPrefix(cfg), cfg), nil
+}
+
+func (r *Runner) processSourceDirectories(ctx context.Context, projectName string, cfg *Config) *ProcessingResult {
+	result := &ProcessingResult{}
+
+	for _, variant := range cfg.Variants {
+		fullPath := filepath.Join(r.cwd, cfg.BasePath, variant.Dir)
+		err := afero.Walk(r.fs, fullPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err // Let afero.Walk handle the error
+			}
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if info.IsDir() || !strings.HasSuffix(info.Name(), ".go") {
+				return nil
+			}
+
+			if err := r.processSourceFile(path, projectName, variant, cfg, result); err != nil {
+				// Collect errors but continue processing other files
+				result.AddFileError(path, fmt.Errorf("processing %s: %w", path, err))
+			}
+			return nil
+		})
+
+		if err != nil {
+			result.AddError(fmt.Errorf("walking directory %s: %w", fullPath, err))
+		}
+	}
+
+	return result
+}
+
+func (r *Runner) processSourceFile(path, projectName string, variant VariantConfig, cfg *Config, result *ProcessingResult) error {
+	rel, err := filepath.Rel(filepath.Join(r.cwd, cfg.BasePath), path)
+	if err != nil {
+		return fmt.Errorf("resolving relative path: %w", err)
+	}
+
+	parts := strings.Split(rel, string(filepath.Separator))
+	if len(parts) < 2 {
+		return nil // Skip files not in the expected structure
+	}
+
+	version := parts[1]
+	prefix := fmt.Sprintf("%s.%s.", projectName, version)
+
+	result.AddFile()
+	return r.processFile(path, prefix, variant, cfg, result)
+}
+
+func (r *Runner) getProjectPrefix(cfg *Config) string {
+	return projectPrefixForCWD(r.cwd, cfg)
+}
+
+// getProjectPrefix derives the project prefix using the real working
+// directory, for callers (such as the Analyzer) that don't hold a Runner.
+func getProjectPrefix(cfg *Config) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "project"
+	}
+	return projectPrefixForCWD(cwd, cfg)
+}
+
+func projectPrefixForCWD(cwd string, cfg *Config) string {
+	repo := os.Getenv(projectEnvVar)
+	if repo != "" {
+		parts := strings.Split(repo, "/")
+		if len(parts) == 2 {
+			return getProjectName(parts[1], cfg)
+		}
+	}
+	return getProjectName(filepath.Base(cwd), cfg)
+}
+
+// processFile rewrites a single source file's swagger annotations through the
+// AST: it parses the file with comments attached, mutates the comment map in
+// place for each annotated type, then re-renders through go/printer and
+// gofmt so the result is always formatter-stable regardless of how the
+// annotation text changed.
+func (r *Runner) processFile(filename, prefix string, variant VariantConfig, cfg *Config, result *ProcessingResult) error {
+	src, err := afero.ReadFile(r.fs, filename)
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("parsing file: %w", err)
+	}
+
+	cmap := ast.NewCommentMap(fset, f, f.Comments)
+	if cmap == nil {
+		// NewCommentMap returns nil rather than an empty map when the file
+		// has no comments yet; we still need somewhere to stash new ones.
+		cmap = make(ast.CommentMap)
+	}
+
+	ignoredTypes := make(map[string]bool)
+	collectionInnerTypes := make(map[string]bool)
+
+	findIgnoredTypes(f, cmap, ignoredTypes)
+	findCollectionInnerTypes(f, ignoredTypes, cfg.CollectionTypes, collectionInnerTypes)
+
+	changed := addAnnotations(f, fset, cmap, variant, cfg, prefix, ignoredTypes, collectionInnerTypes, filename, result)
+	if !changed {
+		return nil
+	}
+
+	// Drop the map down to a sorted comment list, rebuilt from whatever we
+	// mutated above, and hand it back to the file so printer sees it.
+	f.Comments = cmap.Filter(f).Comments()
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, f); err != nil {
+		return fmt.Errorf("printing file: %w", err)
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting output: %w", err)
+	}
+
+	if err := afero.WriteFile(r.fs, filename, formatted, filePermission); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+
+	return nil
+}
+
+// Run drives Analyzer through singlechecker.Main, so the exact same
+// annotation rules RunWithConfig enforces by rewriting files in place can
+// also run as a standalone go vet-compatible checker (see
+// cmd/swagger-annotator-vet), with no second implementation of "is this
+// type's annotation missing or stale" to keep in sync. Unlike
+// RunWithConfig, this never returns: singlechecker.Main parses its own
+// flags and calls os.Exit once analysis completes.
+func Run() {
+	singlechecker.Main(Analyzer)
+}
+
+// RunWithConfig processes the project using the config at configPath, or
+// discovered by upward search when configPath is empty. The returned
+// ProcessingResult carries per-file detail for callers building a
+// structured report; pass it to ProcessingResult.Render for text/json/sarif
+// output.
+func RunWithConfig(configPath string) (*ProcessingResult, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting working directory: %w", err)
+	}
+
+	runner := NewRunner(afero.NewOsFs(), cwd).WithConfigPath(configPath)
+	result, err := runner.Run(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	if result.HasErrors() {
+		return result, fmt.Errorf("encountered %d errors during processing", len(result.Errors))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package annotation
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func newTestRunner(t *testing.T) (*Runner, string) {
+	t.Helper()
+	fs := afero.NewMemMapFs()
+	cwd := "/project"
+	return NewRunner(fs, cwd), cwd
+}
+
+func writeFile(t *testing.T, fs afero.Fs, path, content string) {
+	t.Helper()
+	if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("creating dir for %s: %v", path, err)
+	}
+	if err := afero.WriteFile(fs, path, []byte(content), filePermission); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+}
+
+func TestRunner_IdempotentRerun(t *testing.T) {
+	runner, cwd := newTestRunner(t)
+	path := filepath.Join(cwd, "internal/delivery/http/request/v1/user.go")
+	writeFile(t, runner.Fs(), path, "package request\n\ntype CreateUserReq struct {\n\tName string\n}\n")
+
+	first, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if first.AnnotationsAdded != 1 {
+		t.Fatalf("expected 1 annotation added on first run, got %d", first.AnnotationsAdded)
+	}
+
+	second, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if second.AnnotationsAdded != 0 || second.AnnotationsReplaced != 0 {
+		t.Fatalf("expected no-op rerun, got added=%d replaced=%d", second.AnnotationsAdded, second.AnnotationsReplaced)
+	}
+}
+
+func TestRunner_IgnoreMarkerBothForms(t *testing.T) {
+	runner, cwd := newTestRunner(t)
+	path := filepath.Join(cwd, "internal/delivery/http/response/v1/user.go")
+	writeFile(t, runner.Fs(), path, `package response
+
+// @swagger:ignore
+type InternalRes struct {
+	Name string
+}
+
+type (
+	// @swagger:ignore
+	BlockIgnoredRes struct {
+		Name string
+	}
+
+	KeptRes struct {
+		Name string
+	}
+)
+`)
+
+	result, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	out, err := afero.ReadFile(runner.Fs(), path)
+	if err != nil {
+		t.Fatalf("reading result: %v", err)
+	}
+
+	if strings.Contains(string(out), "InternalRes @name") || strings.Contains(string(out), "@name omp-project.v1.InternalRes") {
+		t.Errorf("InternalRes should have been left unannotated, got:\n%s", out)
+	}
+	if strings.Contains(string(out), "BlockIgnoredRes") && strings.Contains(string(out), "@name") {
+		for _, line := range strings.Split(string(out), "\n") {
+			if strings.Contains(line, "BlockIgnoredRes") && strings.Contains(line, "@name") {
+				t.Errorf("BlockIgnoredRes should have been left unannotated, got line: %s", line)
+			}
+		}
+	}
+	if !strings.Contains(string(out), "@name") || result.AnnotationsAdded != 1 {
+		t.Fatalf("expected exactly KeptRes to be annotated, got added=%d, file:\n%s", result.AnnotationsAdded, out)
+	}
+}
+
+func TestRunner_LastDeclarationInFileTrailingComment(t *testing.T) {
+	runner, cwd := newTestRunner(t)
+	path := filepath.Join(cwd, "internal/delivery/http/request/v1/last.go")
+	writeFile(t, runner.Fs(), path, "package request\n\ntype LastReq struct {\n\tName string\n} // @name omp-project.v1.StaleLastReq\n")
+
+	result, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.AnnotationsReplaced != 1 {
+		t.Fatalf("expected the stale trailing annotation on the last decl to be replaced, got replaced=%d added=%d", result.AnnotationsReplaced, result.AnnotationsAdded)
+	}
+
+	out, err := afero.ReadFile(runner.Fs(), path)
+	if err != nil {
+		t.Fatalf("reading result: %v", err)
+	}
+	if strings.Count(string(out), "@name") != 1 {
+		t.Fatalf("expected exactly one @name comment after rewrite, got:\n%s", out)
+	}
+	if strings.Contains(string(out), "StaleLastReq") {
+		t.Fatalf("stale annotation should have been replaced in place, not duplicated, got:\n%s", out)
+	}
+}
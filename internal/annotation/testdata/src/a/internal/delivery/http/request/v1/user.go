@@ -0,0 +1,20 @@
+package a
+
+// want +3 `CreateUserReq is missing a swagger @name annotation`
+type CreateUserReq struct {
+	Name string
+}
+
+// want +3 `StaleReq has a stale swagger annotation, want "// @name a.v1.StaleReqReq"`
+type StaleReq struct {
+	Name string
+} // @name a.v1.WrongNameReq
+
+// @swagger:ignore
+type InternalReq struct {
+	Secret string
+}
+
+type FreshReq struct {
+	Name string
+} // @name a.v1.FreshReqReq
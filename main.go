@@ -4,31 +4,40 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
 	"swagger-annotator/internal/annotation"
 )
 
 func main() {
 	mode := flag.String("mode", "annotate", "Mode: annotate or check")
+	configPath := flag.String("config", "", "Path to .swagger-annotator.yaml (default: searched upward from cwd)")
+	report := flag.String("report", annotation.ReportText, "Report output format: text, json, or sarif")
 	flag.Parse()
 
 	switch *mode {
 	case "annotate":
-		if err := annotation.Run(); err != nil {
+		result, err := annotation.RunWithConfig(*configPath)
+		printReport(result, *report, false)
+		if err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "annotate failed: %v\n", err)
 			os.Exit(1)
 		}
 	case "check":
-		err := annotation.Run()
+		result, err := annotation.Check(*configPath)
+		if result != nil {
+			printReport(result.Result, *report, true)
+		}
 		if err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "check failed: %v\n", err)
 			os.Exit(1)
 		}
-		// Extra check: if `git status` is dirty, fail
-		out, _ := execCommand("git", "status", "--porcelain")
-		if len(out) > 0 {
-			_, _ = fmt.Fprintln(os.Stderr, "annotation check failed: uncommitted changes found")
-			_, _ = fmt.Fprintln(os.Stderr, string(out))
+		if result.HasChanges() {
+			_, _ = fmt.Fprintln(os.Stderr, "annotation check failed: files are missing up-to-date annotations")
+			for _, path := range result.Changed {
+				_, _ = fmt.Fprintf(os.Stderr, "  changed: %s\n", path)
+			}
+			for _, path := range result.Added {
+				_, _ = fmt.Fprintf(os.Stderr, "  added: %s\n", path)
+			}
 			os.Exit(2)
 		}
 	default:
@@ -37,6 +46,18 @@ func main() {
 	}
 }
 
-func execCommand(name string, args ...string) ([]byte, error) {
-	return exec.Command(name, args...).CombinedOutput()
+// printReport writes result to stdout in the requested format. checkMode is
+// forwarded to ProcessingResult.Render so a SARIF report raises stale
+// annotations to "warning" level instead of the "note" level used when
+// annotate mode fixes them in place.
+func printReport(result *annotation.ProcessingResult, format string, checkMode bool) {
+	if result == nil {
+		return
+	}
+	rendered, err := result.Render(format, checkMode)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "rendering report: %v\n", err)
+		return
+	}
+	fmt.Println(rendered)
 }